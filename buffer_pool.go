@@ -0,0 +1,83 @@
+package jsoniter
+
+import "sync"
+
+// streamBufPool and iterBufPool back Stream.buf / Iterator.buf recycling
+// across Marshal/Unmarshal round trips - the dominant source of
+// allocations for callers doing many of them. Classes run from 256
+// bytes (smaller isn't worth pooling) up to 256KiB; a buffer that grew
+// past the top class is left for the GC instead of pooled, so one
+// oversized payload can't pin a large allocation in the pool forever.
+var streamBufPool = newSizedPool([]int{256, 1024, 4096, 16384, 65536, 262144})
+var iterBufPool = newSizedPool([]int{256, 1024, 4096, 16384, 65536, 262144})
+
+// poolingDisabled tracks configs that opted out via
+// DisableBufferPoolingFor, for callers that retain a Stream's Buffer()
+// past the call and would otherwise have it recycled out from under
+// them.
+var poolingDisabled sync.Map // map[*frozenConfig]bool
+
+// DisableBufferPoolingFor turns off Stream/Iterator buffer pooling for
+// cfg. Intended to be called once, right after Config.Froze().
+func DisableBufferPoolingFor(cfg API) {
+	poolingDisabled.Store(cfg.(*frozenConfig), true)
+}
+
+func buffersPooledFor(cfg *frozenConfig) bool {
+	disabled, _ := poolingDisabled.Load(cfg)
+	return disabled != true
+}
+
+// acquireStreamBuf returns a buffer of at least size bytes of capacity
+// for a Stream being borrowed for cfg, drawing from streamBufPool unless
+// pooling has been disabled for cfg. The pool hands back a class-sized
+// buffer that may be larger than size - callers that need a Stream's
+// buffer to never grow past the bufSize they asked for (see
+// Test_flush_buffer_should_stop_grow_buffer) must reslice-cap the result
+// themselves and keep this return value around (e.g. Stream.rawBuf) to
+// pass to Release/Put later; Put classifies by cap, so pooling a
+// reslice-capped view instead of this one would silently misfile it
+// under the wrong class.
+func acquireStreamBuf(cfg *frozenConfig, size int) []byte {
+	if !buffersPooledFor(cfg) {
+		return make([]byte, 0, size)
+	}
+	return streamBufPool.Get(size)
+}
+
+// acquireIterBuf mirrors acquireStreamBuf for Iterator.buf.
+func acquireIterBuf(cfg *frozenConfig, size int) []byte {
+	if !buffersPooledFor(cfg) {
+		return make([]byte, 0, size)
+	}
+	return iterBufPool.Get(size)
+}
+
+// Release returns the Stream's raw, un-clipped pool buffer to
+// streamBufPool (unless pooling is disabled for its config) and clears
+// the Stream's buffer references. (*frozenConfig).Marshal and
+// Encoder.Close call this once a borrowed Stream's output has been fully
+// written out, right before returning it to streamPool. Do not call
+// Release while still holding onto a Buffer() you got from this Stream
+// - the backing array may be handed to another caller as soon as it is
+// pooled.
+func (stream *Stream) Release() {
+	if stream.rawBuf != nil && buffersPooledFor(stream.cfg) {
+		streamBufPool.Put(stream.rawBuf)
+	}
+	stream.buf = nil
+	stream.rawBuf = nil
+}
+
+// Release returns the Iterator's raw, un-clipped pool buffer to
+// iterBufPool (unless pooling is disabled for its config) and clears the
+// Iterator's buffer references. (*frozenConfig).Unmarshal and
+// Decoder.Close call this once decoding has finished, right before
+// returning a borrowed Iterator to iteratorPool.
+func (iter *Iterator) Release() {
+	if iter.rawBuf != nil && buffersPooledFor(iter.cfg) {
+		iterBufPool.Put(iter.rawBuf)
+	}
+	iter.buf = nil
+	iter.rawBuf = nil
+}
@@ -0,0 +1,91 @@
+package jsoniter
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type smallPooledStruct struct {
+	A int    `json:"a"`
+	B string `json:"b"`
+}
+
+func Test_StreamRelease_clearsBuffer(t *testing.T) {
+	should := require.New(t)
+	stream := NewStream(ConfigDefault, nil, 256)
+	stream.WriteVal(smallPooledStruct{A: 1, B: "x"})
+	stream.Release()
+	should.Nil(stream.buf)
+}
+
+func Test_IteratorRelease_clearsBuffer(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, `{"a":1,"b":"x"}`)
+	iter.Release()
+	should.Nil(iter.buf)
+}
+
+func Test_DisableBufferPoolingFor_skipsPool(t *testing.T) {
+	should := require.New(t)
+	cfg := Config{}.Froze()
+	DisableBufferPoolingFor(cfg)
+
+	stream := NewStream(cfg, nil, 256)
+	stream.WriteVal(smallPooledStruct{A: 1, B: "x"})
+	buf := acquireStreamBuf(cfg.(*frozenConfig), 256)
+	should.NotNil(buf)
+	stream.Release()
+	should.Nil(stream.buf)
+}
+
+func Test_acquireIterBuf_nonClassSizeDoesNotCorruptPool(t *testing.T) {
+	should := require.New(t)
+
+	// 512 isn't one of iterBufPool's classes (256/1024/...), so
+	// NewDecoder's hardcoded acquireIterBuf(cfg, 512) used to come back
+	// reslice-capped to exactly 512 and get filed under the 1024 class
+	// on Close. A later Get for that class could then hand out a
+	// buffer whose real capacity was only 512, and a caller asking for
+	// anything in (512, 1024] - like Parse(cfg, r, 900) below - would
+	// reslice-cap past its actual capacity and panic.
+	for i := 0; i < 8; i++ {
+		dec := ConfigDefault.NewDecoder(nil)
+		dec.Close()
+	}
+
+	should.NotPanics(func() {
+		iter := Parse(ConfigDefault, strings.NewReader("123"), 900)
+		should.Equal("123", iter.ReadNumberAsString())
+	})
+}
+
+func Test_MarshalPool_boundedHeapGrowth(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+	should := require.New(t)
+
+	const total = 1000000
+	const workers = 32
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	each := total / workers
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				ConfigDefault.Marshal(smallPooledStruct{A: i, B: "hello"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	should.Less(m.HeapAlloc, uint64(64*1024*1024))
+}
@@ -0,0 +1,159 @@
+package jsoniter
+
+import (
+	"io"
+	"sync"
+)
+
+// Config customizes the behavior of an API instance created via Froze.
+type Config struct {
+	// IndentionStep is the number of spaces to indent each nesting level
+	// by. Zero (the default) produces compact output.
+	IndentionStep int
+}
+
+// API is the frozen, ready-to-use form of a Config.
+type API interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewEncoder(w io.Writer) *Encoder
+	NewDecoder(r io.Reader) *Decoder
+}
+
+// frozenConfig is the concrete, immutable API implementation produced by
+// Config.Froze. streamPool/iteratorPool recycle whole Stream/Iterator
+// objects (and, via acquireStreamBuf/acquireIterBuf and Release, their
+// backing buffers too) across Marshal/Unmarshal calls.
+type frozenConfig struct {
+	configBeforeFrozen Config
+	indentionStep      int
+	streamPool         *sync.Pool
+	iteratorPool       *sync.Pool
+}
+
+// Froze turns a Config into a usable, immutable API instance.
+func (cfg Config) Froze() API {
+	fc := &frozenConfig{configBeforeFrozen: cfg, indentionStep: cfg.IndentionStep}
+	fc.streamPool = &sync.Pool{New: func() interface{} { return NewStream(fc, nil, 256) }}
+	fc.iteratorPool = &sync.Pool{New: func() interface{} { return NewIterator(fc) }}
+	return fc
+}
+
+// ConfigDefault is the default, compact-output API instance.
+var ConfigDefault = Config{}.Froze()
+
+// borrowStream pulls a Stream out of cfg.streamPool, resetting it to
+// write to out and making sure it has a buffer (Release may have
+// cleared it).
+func (cfg *frozenConfig) borrowStream(out io.Writer) *Stream {
+	stream := cfg.streamPool.Get().(*Stream)
+	stream.Reset(out)
+	if stream.buf == nil {
+		stream.rawBuf = acquireStreamBuf(cfg, 256)
+		stream.buf = stream.rawBuf[:0:256]
+	}
+	return stream
+}
+
+// returnStream releases the Stream's buffer back to streamBufPool and
+// returns the Stream itself to cfg.streamPool.
+func (cfg *frozenConfig) returnStream(stream *Stream) {
+	stream.Release()
+	cfg.streamPool.Put(stream)
+}
+
+// borrowIterator pulls an Iterator out of cfg.iteratorPool and resets it
+// to read data.
+func (cfg *frozenConfig) borrowIterator(data []byte) *Iterator {
+	iter := cfg.iteratorPool.Get().(*Iterator)
+	iter.ResetBytes(data)
+	return iter
+}
+
+// returnIterator releases the Iterator's buffer back to iterBufPool and
+// returns the Iterator itself to cfg.iteratorPool.
+func (cfg *frozenConfig) returnIterator(iter *Iterator) {
+	iter.Release()
+	cfg.iteratorPool.Put(iter)
+}
+
+// Marshal encodes v as JSON, borrowing a pooled Stream (and its pooled
+// buffer) for the duration of the call.
+func (cfg *frozenConfig) Marshal(v interface{}) ([]byte, error) {
+	stream := cfg.borrowStream(nil)
+	defer cfg.returnStream(stream)
+	stream.WriteVal(v)
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	result := make([]byte, len(stream.buf))
+	copy(result, stream.buf)
+	return result, nil
+}
+
+// Unmarshal decodes data into v, borrowing a pooled Iterator (and its
+// pooled buffer) for the duration of the call.
+func (cfg *frozenConfig) Unmarshal(data []byte, v interface{}) error {
+	iter := cfg.borrowIterator(data)
+	defer cfg.returnIterator(iter)
+	iter.ReadVal(v)
+	return iter.Error
+}
+
+// Encoder writes a stream of JSON values, mirroring encoding/json.Encoder.
+type Encoder struct {
+	stream *Stream
+}
+
+// NewEncoder returns an Encoder that writes to w, borrowing a pooled
+// Stream for its lifetime.
+func (cfg *frozenConfig) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{stream: cfg.borrowStream(w)}
+}
+
+// Encode writes the JSON encoding of v to the Encoder's writer.
+func (enc *Encoder) Encode(v interface{}) error {
+	enc.stream.WriteVal(v)
+	if enc.stream.Error != nil {
+		return enc.stream.Error
+	}
+	return enc.stream.Flush()
+}
+
+// Close releases the Encoder's pooled Stream back to its config. Callers
+// that create many short-lived Encoders should call this when done.
+func (enc *Encoder) Close() {
+	enc.stream.cfg.returnStream(enc.stream)
+}
+
+// Decoder reads a stream of JSON values, mirroring encoding/json.Decoder.
+type Decoder struct {
+	iter *Iterator
+}
+
+// NewDecoder returns a Decoder that reads from r, borrowing a pooled
+// Iterator for its lifetime.
+func (cfg *frozenConfig) NewDecoder(r io.Reader) *Decoder {
+	iter := cfg.borrowIterator(nil)
+	iter.Reset(r)
+	if iter.buf == nil {
+		if iter.rawBuf == nil {
+			iter.rawBuf = acquireIterBuf(cfg, 512)
+		}
+		iter.buf = iter.rawBuf[:cap(iter.rawBuf)]
+	}
+	return &Decoder{iter: iter}
+}
+
+// Decode reads the next JSON-encoded value from the Decoder's reader.
+func (dec *Decoder) Decode(v interface{}) error {
+	dec.iter.ReadVal(v)
+	return dec.iter.Error
+}
+
+// Close releases the Decoder's pooled Iterator back to its config.
+// Callers that create many short-lived Decoders should call this when
+// done.
+func (dec *Decoder) Close() {
+	dec.iter.cfg.returnIterator(dec.iter)
+}
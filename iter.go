@@ -0,0 +1,176 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+)
+
+// ValueType identifies the JSON type of the value the Iterator is
+// currently positioned on.
+type ValueType int
+
+// The JSON value types an Iterator can be positioned on.
+const (
+	InvalidValue ValueType = iota
+	StringValue
+	NumberValue
+	NilValue
+	BoolValue
+	ArrayValue
+	ObjectValue
+)
+
+// Iterator is a io.Reader-like object, with JSON specific read
+// functions. Errors are not returned as return values, but stored as
+// the Error field on the Iterator instance instead.
+type Iterator struct {
+	cfg         *frozenConfig
+	reader      io.Reader
+	buf         []byte
+	head        int
+	tail        int
+	depth       int
+	numberSpill []byte
+	Error       error
+	Attachment  interface{}
+
+	// rawBuf is the un-clipped buffer acquireIterBuf handed back, when
+	// buf was drawn from the pool (Parse); Release pools rawBuf, not
+	// buf, since buf may be clipped to the caller's bufSize (or, via ParseBytes,
+	// input that never went through the pool at all - then rawBuf stays
+	// nil and Release leaves the pool alone).
+	rawBuf []byte
+}
+
+// NewIterator creates an empty Iterator instance.
+func NewIterator(cfg API) *Iterator {
+	return &Iterator{cfg: cfg.(*frozenConfig)}
+}
+
+// Parse creates an Iterator instance that reads from reader, using a
+// buffer of bufSize bytes to stage input.
+func Parse(cfg API, reader io.Reader, bufSize int) *Iterator {
+	fc := cfg.(*frozenConfig)
+	raw := acquireIterBuf(fc, bufSize)
+	return &Iterator{cfg: fc, reader: reader, buf: raw[:bufSize:bufSize], rawBuf: raw}
+}
+
+// ParseBytes creates an Iterator instance that reads directly out of
+// input.
+func ParseBytes(cfg API, input []byte) *Iterator {
+	return &Iterator{cfg: cfg.(*frozenConfig), buf: input, tail: len(input)}
+}
+
+// ParseString creates an Iterator instance that reads directly out of
+// input.
+func ParseString(cfg API, input string) *Iterator {
+	return ParseBytes(cfg, []byte(input))
+}
+
+// Reset reuses this Iterator to read from a new reader, keeping its
+// current buffer.
+func (iter *Iterator) Reset(reader io.Reader) *Iterator {
+	iter.releaseNumberSpill()
+	iter.reader = reader
+	iter.head = 0
+	iter.tail = 0
+	iter.depth = 0
+	iter.Error = nil
+	return iter
+}
+
+// ResetBytes reuses this Iterator to read directly out of input.
+func (iter *Iterator) ResetBytes(input []byte) *Iterator {
+	iter.releaseNumberSpill()
+	iter.reader = nil
+	iter.buf = input
+	iter.head = 0
+	iter.tail = len(input)
+	iter.depth = 0
+	iter.Error = nil
+	return iter
+}
+
+// releaseNumberSpill returns the spill slice kept by the last
+// ReadNumberAsString call, if any, to numberSpillPool. Iterator.buf is
+// released separately by Release; see buffer_pool.go.
+func (iter *Iterator) releaseNumberSpill() {
+	if iter.numberSpill != nil {
+		numberSpillPool.Put(iter.numberSpill)
+		iter.numberSpill = nil
+	}
+}
+
+// loadMore refills the iterator's buffer from its reader. It reports
+// false once the reader is exhausted or errored, leaving iter.Error set.
+func (iter *Iterator) loadMore() bool {
+	if iter.reader == nil {
+		if iter.Error == nil {
+			iter.head = iter.tail
+			iter.Error = io.EOF
+		}
+		return false
+	}
+	for {
+		n, err := iter.reader.Read(iter.buf[:cap(iter.buf)])
+		if n > 0 {
+			iter.head = 0
+			iter.tail = n
+			return true
+		}
+		if err != nil {
+			if iter.Error == nil {
+				iter.Error = err
+			}
+			return false
+		}
+	}
+}
+
+// readByte returns the next byte, refilling the buffer as needed. It
+// returns 0 once the iterator is exhausted (iter.Error records why).
+func (iter *Iterator) readByte() byte {
+	if iter.head == iter.tail {
+		if !iter.loadMore() {
+			return 0
+		}
+	}
+	b := iter.buf[iter.head]
+	iter.head++
+	return b
+}
+
+// unreadByte steps back one byte read via readByte.
+func (iter *Iterator) unreadByte() {
+	if iter.head == 0 {
+		panic("unread byte out of range")
+	}
+	iter.head--
+}
+
+// ReportError records msg as the reason operation failed, unless an
+// error (other than io.EOF) has already been recorded.
+func (iter *Iterator) ReportError(operation string, msg string) {
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	iter.Error = fmt.Errorf("%s: %s", operation, msg)
+}
+
+// skipWhitespace advances past any run of JSON whitespace.
+func (iter *Iterator) skipWhitespace() {
+	for {
+		for i := iter.head; i < iter.tail; i++ {
+			switch iter.buf[i] {
+			case ' ', '\t', '\n', '\r':
+				continue
+			default:
+				iter.head = i
+				return
+			}
+		}
+		if !iter.loadMore() {
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package jsoniter
+
+import (
+	"io"
+	"unsafe"
+)
+
+// ReadNumberAsString reads the current JSON number token and returns it
+// verbatim, without routing it through float64/int64 conversion first.
+// When the number lies entirely inside the iterator's current buffer
+// window, the returned string aliases that buffer directly and is valid
+// only until the next call made on this Iterator. When the number
+// straddles a loadMore refill, the bytes are copied into a slice
+// borrowed from numberSpillPool instead - still aliased, still only
+// valid until the next call. Use CopyNumberAsString if the string needs
+// to outlive that.
+func (iter *Iterator) ReadNumberAsString() string {
+	str := iter.appendNumberBytes(nil)
+	if str == nil {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&str))
+}
+
+// CopyNumberAsString reads the current JSON number token into a freshly
+// allocated string, safe to retain past the next call on this Iterator.
+func (iter *Iterator) CopyNumberAsString() string {
+	return string(iter.AppendNumberBytes(nil))
+}
+
+// AppendNumberBytes reads the current JSON number token and appends its
+// raw bytes to dst, returning the extended slice. Unlike
+// ReadNumberAsString it never aliases the iterator's internal buffer, so
+// the result is always safe to retain.
+func (iter *Iterator) AppendNumberBytes(dst []byte) []byte {
+	if dst == nil {
+		dst = make([]byte, 0, 16)
+	}
+	return iter.appendNumberBytes(dst)
+}
+
+// appendNumberBytes is shared by ReadNumberAsString and
+// AppendNumberBytes. A nil dst selects the zero-copy fast path (aliasing
+// iter.buf, or the pooled spill slice on a straddling read); a non-nil
+// dst always copies into dst and immediately returns any spill slice to
+// numberSpillPool, since nothing keeps aliasing it afterwards.
+//
+// Any spill slice retained by a previous nil-dst call is released back
+// to numberSpillPool here, at the start of the next call on this
+// Iterator - which is exactly the point past which ReadNumberAsString's
+// doc comment says its result is no longer valid.
+func (iter *Iterator) appendNumberBytes(dst []byte) []byte {
+	iter.releaseNumberSpill()
+	for i := iter.head; i < iter.tail; i++ {
+		switch iter.buf[i] {
+		case '+', '-', '.', 'e', 'E', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			continue
+		default:
+			str := iter.buf[iter.head:i]
+			iter.head = i
+			return iter.finishNumberBytes(str, dst, false)
+		}
+	}
+	return iter.appendNumberBytesSlow(dst)
+}
+
+// appendNumberBytesSlow handles a number that runs off the end of the
+// current buffer window and needs at least one loadMore to finish.
+func (iter *Iterator) appendNumberBytesSlow(dst []byte) []byte {
+	readLen := iter.tail - iter.head
+	spill := numberSpillPool.Get(readLen * 2)
+	spill = append(spill, iter.buf[iter.head:iter.tail]...)
+	iter.head = iter.tail
+	for iter.Error == nil {
+		c := iter.readByte()
+		switch c {
+		case '+', '-', '.', 'e', 'E', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			spill = append(spill, c)
+		default:
+			if iter.Error == nil {
+				// readByte only leaves iter.Error unset when it actually
+				// consumed a real byte (rather than hitting EOF without
+				// advancing iter.head), so unreadByte has something to
+				// undo here.
+				iter.unreadByte()
+			}
+			return iter.finishNumberBytes(spill, dst, true)
+		}
+	}
+	return iter.finishNumberBytes(spill, dst, true)
+}
+
+// finishNumberBytes validates the parsed run and, on the copying path,
+// appends it to dst and releases a pooled spill slice back to
+// numberSpillPool.
+func (iter *Iterator) finishNumberBytes(str, dst []byte, pooled bool) []byte {
+	if iter.Error != nil && iter.Error != io.EOF {
+		if pooled {
+			numberSpillPool.Put(str)
+		}
+		return nil
+	}
+	if len(str) == 0 {
+		if pooled {
+			numberSpillPool.Put(str)
+		}
+		iter.ReportError("ReadNumberAsString", "invalid number")
+		return nil
+	}
+	if dst == nil {
+		if pooled {
+			// Keep the spill alive until the next call on this Iterator
+			// instead of returning it to the pool immediately - the
+			// caller is still holding a string that aliases it.
+			iter.numberSpill = str
+		}
+		return str
+	}
+	dst = append(dst, str...)
+	if pooled {
+		numberSpillPool.Put(str)
+	}
+	return dst
+}
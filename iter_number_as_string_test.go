@@ -0,0 +1,78 @@
+package jsoniter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadNumberAsString_fastPath(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, `1.2345,`)
+	should.Equal("1.2345", iter.ReadNumberAsString())
+}
+
+func Test_ReadNumberAsString_spansBufferRefill(t *testing.T) {
+	should := require.New(t)
+	// bufSize of 4 forces loadMore to be called mid-number.
+	iter := Parse(ConfigDefault, strings.NewReader(`123456789.123,`), 4)
+	should.Equal("123456789.123", iter.ReadNumberAsString())
+}
+
+func Test_CopyNumberAsString_detachesFromPool(t *testing.T) {
+	should := require.New(t)
+	iter := Parse(ConfigDefault, strings.NewReader(`123456789.123,`), 4)
+	should.Equal("123456789.123", iter.CopyNumberAsString())
+}
+
+func Test_AppendNumberBytes_appendsToExistingSlice(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, `3.14,`)
+	buf := iter.AppendNumberBytes([]byte("prefix:"))
+	should.Equal("prefix:3.14", string(buf))
+}
+
+func Test_ReadNumberAsString_invalidNumber(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, `,`)
+	iter.ReadNumberAsString()
+	should.NotNil(iter.Error)
+}
+
+func Test_ReadNumberAsString_emptyInputReportsErrorInsteadOfPanicking(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, ``)
+	should.Equal("", iter.ReadNumberAsString())
+	should.NotNil(iter.Error)
+}
+
+func Test_ReadNumberAsString_straddleSpillIsRecycled(t *testing.T) {
+	should := require.New(t)
+	// A small bufSize forces every number to straddle a loadMore.
+	iter := Parse(ConfigDefault, strings.NewReader(strings.Repeat("123456789.123,", 200)), 4)
+
+	// The next call's appendNumberBytes releases the previous spill back
+	// to numberSpillPool before drawing a new one, so after one warm-up
+	// call the steady state should just recycle the same pooled buffer
+	// instead of leaking a fresh allocation every time.
+	should.Equal("123456789.123", iter.ReadNumberAsString())
+	iter.readByte() // consume the trailing comma
+
+	allocs := testing.AllocsPerRun(100, func() {
+		iter.ReadNumberAsString()
+		iter.readByte()
+	})
+	should.LessOrEqual(allocs, float64(1))
+}
+
+func Test_ReadNumberAsString_noAllocOnFastPath(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`123.456,`)
+	iter := ParseBytes(ConfigDefault, input)
+	allocs := testing.AllocsPerRun(1000, func() {
+		iter.ResetBytes(input)
+		iter.ReadNumberAsString()
+	})
+	should.Equal(float64(0), allocs)
+}
@@ -0,0 +1,255 @@
+package jsoniter
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// ReadVal decodes the next JSON value into obj, which must be a non-nil
+// pointer.
+func (iter *Iterator) ReadVal(obj interface{}) {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		iter.ReportError("ReadVal", "obj must be a non-nil pointer")
+		return
+	}
+	iter.readReflectVal(rv.Elem())
+}
+
+// readReflectVal decodes the next JSON value into rv, which must be
+// addressable and settable.
+func (iter *Iterator) readReflectVal(rv reflect.Value) {
+	iter.skipWhitespace()
+	c := iter.readByte()
+	if iter.Error != nil {
+		return
+	}
+	switch c {
+	case 'n':
+		iter.skipLiteral("ull")
+		rv.Set(reflect.Zero(rv.Type()))
+	case 't':
+		iter.skipLiteral("rue")
+		iter.setBool(rv, true)
+	case 'f':
+		iter.skipLiteral("alse")
+		iter.setBool(rv, false)
+	case '"':
+		iter.unreadByte()
+		iter.setString(rv, iter.readStringBody())
+	case '[':
+		iter.readArrayInto(rv)
+	case '{':
+		iter.readObjectInto(rv)
+	default:
+		iter.unreadByte()
+		iter.setNumber(rv, iter.ReadNumberAsString())
+	}
+}
+
+func (iter *Iterator) setBool(rv reflect.Value, val bool) {
+	switch rv.Kind() {
+	case reflect.Bool:
+		rv.SetBool(val)
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(val))
+	}
+}
+
+func (iter *Iterator) setString(rv reflect.Value, val string) {
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(val)
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(val))
+	}
+}
+
+func (iter *Iterator) setNumber(rv reflect.Value, numStr string) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(numStr, 10, 64)
+		if err != nil {
+			iter.ReportError("ReadVal", "invalid number: "+numStr)
+			return
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(numStr, 10, 64)
+		if err != nil {
+			iter.ReportError("ReadVal", "invalid number: "+numStr)
+			return
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(numStr, 64)
+		if err != nil {
+			iter.ReportError("ReadVal", "invalid number: "+numStr)
+			return
+		}
+		rv.SetFloat(f)
+	case reflect.Interface:
+		f, err := strconv.ParseFloat(numStr, 64)
+		if err != nil {
+			iter.ReportError("ReadVal", "invalid number: "+numStr)
+			return
+		}
+		rv.Set(reflect.ValueOf(f))
+	}
+}
+
+// skipLiteral consumes the remaining bytes of a literal (e.g. "ull"
+// after already reading the leading 'n' of "null").
+func (iter *Iterator) skipLiteral(rest string) {
+	for i := 0; i < len(rest); i++ {
+		if iter.readByte() != rest[i] {
+			iter.ReportError("skipLiteral", "invalid literal")
+			return
+		}
+	}
+}
+
+// readStringBody reads a double-quoted JSON string (the opening quote
+// must still be unread) and returns its decoded contents.
+func (iter *Iterator) readStringBody() string {
+	iter.readByte() // opening quote
+	var out []byte
+	for {
+		c := iter.readByte()
+		if iter.Error != nil {
+			return string(out)
+		}
+		switch c {
+		case '"':
+			return string(out)
+		case '\\':
+			e := iter.readByte()
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 't':
+				out = append(out, '\t')
+			case 'r':
+				out = append(out, '\r')
+			default:
+				out = append(out, e)
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+}
+
+// readArrayInto decodes a JSON array into rv, which must be a slice.
+func (iter *Iterator) readArrayInto(rv reflect.Value) {
+	if rv.Kind() != reflect.Slice {
+		iter.ReportError("ReadVal", "cannot decode array into "+rv.Kind().String())
+		return
+	}
+	rv.Set(reflect.MakeSlice(rv.Type(), 0, 4))
+	iter.skipWhitespace()
+	if iter.peekByteIs(']') {
+		iter.readByte()
+		return
+	}
+	for {
+		elem := reflect.New(rv.Type().Elem()).Elem()
+		iter.readReflectVal(elem)
+		rv.Set(reflect.Append(rv, elem))
+		iter.skipWhitespace()
+		c := iter.readByte()
+		if c == ']' || iter.Error != nil {
+			return
+		}
+		iter.skipWhitespace()
+	}
+}
+
+// readObjectInto decodes a JSON object into rv, which must be a struct
+// or a map[string]T.
+func (iter *Iterator) readObjectInto(rv reflect.Value) {
+	iter.skipWhitespace()
+	if iter.peekByteIs('}') {
+		iter.readByte()
+		return
+	}
+	var structEnc *structEncoder
+	if rv.Kind() == reflect.Struct {
+		structEnc = encoderOfStruct(rv.Type())
+	} else if rv.Kind() == reflect.Map && rv.IsNil() {
+		rv.Set(reflect.MakeMap(rv.Type()))
+	}
+	for {
+		iter.skipWhitespace()
+		key := iter.readStringBody()
+		iter.skipWhitespace()
+		iter.readByte() // ':'
+		switch {
+		case structEnc != nil:
+			iter.readObjectFieldInto(rv, structEnc, key)
+		case rv.Kind() == reflect.Map:
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			iter.readReflectVal(elem)
+			rv.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), elem)
+		default:
+			var discard interface{}
+			iter.readReflectVal(reflect.ValueOf(&discard).Elem())
+		}
+		iter.skipWhitespace()
+		c := iter.readByte()
+		if c == '}' || iter.Error != nil {
+			return
+		}
+	}
+}
+
+// readObjectFieldInto decodes the value for key directly into the
+// struct field it names, falling back to skipping unknown keys.
+func (iter *Iterator) readObjectFieldInto(rv reflect.Value, enc *structEncoder, key string) {
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" && tag != "-" {
+			if idx := indexByte(tag, ','); idx != -1 {
+				if tag[:idx] != "" {
+					name = tag[:idx]
+				}
+			} else {
+				name = tag
+			}
+		}
+		if name == key {
+			iter.readReflectVal(rv.Field(i))
+			return
+		}
+	}
+	_ = enc
+	var discard interface{}
+	iter.readReflectVal(reflect.ValueOf(&discard).Elem())
+}
+
+func indexByte(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// peekByteIs reports whether the next unread byte equals c, without
+// consuming it.
+func (iter *Iterator) peekByteIs(c byte) bool {
+	b := iter.readByte()
+	if iter.Error != nil {
+		return false
+	}
+	if b == c {
+		return true
+	}
+	iter.unreadByte()
+	return false
+}
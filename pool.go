@@ -0,0 +1,64 @@
+package jsoniter
+
+import "sync"
+
+// sizedPool is a tiered free-list of byte slices keyed by power-of-two
+// capacity classes, similar in spirit to the go-buffer-pool design. Get
+// returns a slice from the smallest class able to hold the requested
+// size; Put returns a slice to the largest class its capacity covers, and
+// silently drops slices whose capacity exceeds the largest configured
+// class so the pool cannot grow without bound.
+type sizedPool struct {
+	classes []int
+	pools   []sync.Pool
+}
+
+// newSizedPool builds a sizedPool over classes, which must be sorted
+// ascending.
+func newSizedPool(classes []int) *sizedPool {
+	p := &sizedPool{classes: classes, pools: make([]sync.Pool, len(classes))}
+	for i, class := range classes {
+		size := class
+		p.pools[i].New = func() interface{} {
+			buf := make([]byte, 0, size)
+			return &buf
+		}
+	}
+	return p
+}
+
+// Get returns a zero-length slice with capacity >= size, drawn from the
+// smallest matching class. If size exceeds every configured class, a
+// fresh slice is allocated directly instead of growing the top pool.
+func (p *sizedPool) Get(size int) []byte {
+	for i, class := range p.classes {
+		if class >= size {
+			ptr := p.pools[i].Get().(*[]byte)
+			return (*ptr)[:0]
+		}
+	}
+	return make([]byte, 0, size)
+}
+
+// Put returns buf to the largest class whose size does not exceed its
+// capacity, so every slice a class hands out from Get holds at least the
+// class size. A capacity above the largest configured class, or below the
+// smallest, means it is simply dropped.
+func (p *sizedPool) Put(buf []byte) {
+	c := cap(buf)
+	if len(p.classes) == 0 || c > p.classes[len(p.classes)-1] {
+		return
+	}
+	for i := len(p.classes) - 1; i >= 0; i-- {
+		if p.classes[i] <= c {
+			buf = buf[:0]
+			p.pools[i].Put(&buf)
+			return
+		}
+	}
+}
+
+// numberSpillPool backs the rare case in ReadNumberAsString /
+// AppendNumberBytes where a number straddles a loadMore refill and has
+// to be copied out of the iterator's buffer instead of aliased in place.
+var numberSpillPool = newSizedPool([]int{16, 32, 64, 128, 256, 1024})
@@ -0,0 +1,117 @@
+package jsoniter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"unsafe"
+)
+
+// ValEncoder encodes a single value addressed by ptr onto stream. It is
+// the extension point struct field encoders (see
+// reflect_struct_encoder.go) and frozenFieldEncoder are built around.
+type ValEncoder interface {
+	IsEmpty(ptr unsafe.Pointer) bool
+	Encode(ptr unsafe.Pointer, stream *Stream)
+}
+
+// WriteVal encodes val as JSON into the stream.
+func (stream *Stream) WriteVal(val interface{}) {
+	if val == nil {
+		stream.WriteNil()
+		return
+	}
+	stream.writeReflectVal(reflect.ValueOf(val))
+}
+
+// writeReflectVal dispatches on rv's Kind. It encodes both a value passed
+// directly to WriteVal and each struct field value written by
+// reflectFieldEncoder; a struct's field list comes from encoderOfStruct.
+func (stream *Stream) writeReflectVal(rv reflect.Value) {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			stream.WriteNil()
+			return
+		}
+		stream.writeReflectVal(rv.Elem())
+	case reflect.Interface:
+		if rv.IsNil() {
+			stream.WriteNil()
+			return
+		}
+		stream.writeReflectVal(rv.Elem())
+	case reflect.Struct:
+		stream.writeStruct(rv)
+	case reflect.Slice, reflect.Array:
+		stream.writeSlice(rv)
+	case reflect.Map:
+		stream.writeMap(rv)
+	case reflect.String:
+		stream.WriteString(rv.String())
+	case reflect.Bool:
+		stream.WriteBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		stream.buf = strconv.AppendInt(stream.buf, rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		stream.buf = strconv.AppendUint(stream.buf, rv.Uint(), 10)
+	case reflect.Float32:
+		stream.buf = strconv.AppendFloat(stream.buf, rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		stream.buf = strconv.AppendFloat(stream.buf, rv.Float(), 'g', -1, 64)
+	default:
+		if stream.Error == nil {
+			stream.Error = fmt.Errorf("jsoniter: unsupported type %s", rv.Type())
+		}
+	}
+}
+
+// writeStruct encodes rv (a struct value) using its cached encoderOfStruct.
+func (stream *Stream) writeStruct(rv reflect.Value) {
+	if !rv.CanAddr() {
+		addr := reflect.New(rv.Type())
+		addr.Elem().Set(rv)
+		rv = addr.Elem()
+	}
+	ptr := unsafe.Pointer(rv.UnsafeAddr())
+	enc := encoderOfStruct(rv.Type())
+	stream.WriteObjectStart()
+	for i, field := range enc.fields {
+		if i != 0 {
+			stream.WriteMore()
+		}
+		field.Encode(ptr, stream)
+	}
+	stream.WriteObjectEnd()
+}
+
+// writeSlice encodes rv (a slice or array value) element by element.
+func (stream *Stream) writeSlice(rv reflect.Value) {
+	stream.WriteArrayStart()
+	for i := 0; i < rv.Len(); i++ {
+		if i != 0 {
+			stream.WriteMore()
+		}
+		stream.writeReflectVal(rv.Index(i))
+	}
+	stream.WriteArrayEnd()
+}
+
+// writeMap encodes rv (a map value) with string-keyed entries, sorted by
+// key for deterministic output.
+func (stream *Stream) writeMap(rv reflect.Value) {
+	stream.WriteObjectStart()
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	for i, key := range keys {
+		if i != 0 {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(fmt.Sprint(key.Interface()))
+		stream.writeReflectVal(rv.MapIndex(key))
+	}
+	stream.WriteObjectEnd()
+}
@@ -0,0 +1,78 @@
+package jsoniter
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// frozenFieldKey identifies a `json:"name,frozen"` field by the struct
+// type that declares it and its JSON name.
+type frozenFieldKey struct {
+	typ   reflect.Type
+	field string
+}
+
+// frozenFieldRegistry holds the registered raw-JSON fragment for each
+// frozenFieldKey - see RegisterFrozenField. A frozen-tagged field with no
+// registration is encoded live like any other field, so there is no path
+// by which one struct instance's value can leak into another's encoding.
+var frozenFieldRegistry sync.Map // map[frozenFieldKey][]byte
+
+// RegisterFrozenField pins the raw JSON bytes a `json:"name,frozen"`
+// field on typ encodes to for every value of that type, instead of
+// letting the struct encoder infer them from whichever instance it
+// happens to encode first - inferring from a live instance silently
+// serves that instance's value to every other instance of the type,
+// which is only correct if the field really is a compile-time constant.
+// rawValue is written verbatim via WriteRawField, so it must already be
+// valid JSON (e.g. `"v1.2.3"`).
+//
+// Call this during init, before typ is first encoded or decoded:
+// encoderOfStruct caches the compiled field list per type on first use, so a
+// registration added afterwards has no effect on that type.
+func RegisterFrozenField(typ reflect.Type, fieldName string, rawValue []byte) {
+	stream := &Stream{}
+	stream.WriteRawField(fieldName, rawValue)
+	fragment := make([]byte, len(stream.buf))
+	copy(fragment, stream.buf)
+	frozenFieldRegistry.Store(frozenFieldKey{typ: typ, field: fieldName}, fragment)
+}
+
+// frozenFieldEncoder writes a fragment registered via RegisterFrozenField
+// on every Encode call, ignoring the struct instance entirely. It is
+// only ever constructed for a (type, field) pair that already has a
+// registered fragment; see buildStructEncoder.
+type frozenFieldEncoder struct {
+	fragment []byte
+}
+
+func newFrozenFieldEncoder(fragment []byte) *frozenFieldEncoder {
+	return &frozenFieldEncoder{fragment: fragment}
+}
+
+// IsEmpty reports whether the registered fragment is empty. A frozen
+// field is never "empty" in the omitempty sense once it has a
+// registration - an empty fragment only happens if one was never set.
+func (encoder *frozenFieldEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return len(encoder.fragment) == 0
+}
+
+// Encode writes the registered `"name":value` fragment verbatim.
+func (encoder *frozenFieldEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	stream.Write(encoder.fragment)
+}
+
+// hasFrozenOption reports whether a struct tag's comma-separated option
+// list (the part after the field name) contains "frozen". The struct
+// encoder generator checks this while building a field's encoder, and
+// looks up a registered fragment for it via frozenFieldRegistry.
+func hasFrozenOption(tagOptions string) bool {
+	for _, opt := range strings.Split(tagOptions, ",") {
+		if opt == "frozen" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package jsoniter
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeValEncoder is a minimal ValEncoder used by BenchmarkUnfrozenFieldEncoder
+// as a live-encoding baseline to compare frozenFieldEncoder against.
+type fakeValEncoder struct {
+	calls int
+	write func(stream *Stream)
+}
+
+func (e *fakeValEncoder) IsEmpty(ptr unsafe.Pointer) bool { return false }
+
+func (e *fakeValEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	e.calls++
+	e.write(stream)
+}
+
+func Test_frozenFieldEncoder_writesRegisteredFragment(t *testing.T) {
+	should := require.New(t)
+	encoder := newFrozenFieldEncoder([]byte(`"kind":"enum-value"`))
+
+	stream := NewStream(ConfigDefault, nil, 64)
+	encoder.Encode(nil, stream)
+	should.Equal(`"kind":"enum-value"`, string(stream.Buffer()))
+
+	// The fragment is fixed at construction time, so a second Stream (a
+	// stand-in for a second struct instance) gets the exact same bytes.
+	stream2 := NewStream(ConfigDefault, nil, 64)
+	encoder.Encode(nil, stream2)
+	should.Equal(`"kind":"enum-value"`, string(stream2.Buffer()))
+}
+
+func Test_hasFrozenOption(t *testing.T) {
+	should := require.New(t)
+	should.True(hasFrozenOption("omitempty,frozen"))
+	should.True(hasFrozenOption("frozen"))
+	should.False(hasFrozenOption("omitempty"))
+}
+
+type responseMeta struct {
+	Kind    string `json:"kind,frozen"`
+	Version string `json:"version,frozen"`
+	ID      int    `json:"id"`
+}
+
+// responseMeta's frozen fields must be registered before the type's first
+// encode anywhere in this binary - encoderOfStruct caches the compiled
+// field list per type on first use.
+func init() {
+	RegisterFrozenField(reflect.TypeOf(responseMeta{}), "kind", []byte(`"response"`))
+	RegisterFrozenField(reflect.TypeOf(responseMeta{}), "version", []byte(`"v1.2.3"`))
+}
+
+func Test_WriteVal_frozenTag_usesRegisteredFragment(t *testing.T) {
+	should := require.New(t)
+
+	v1 := responseMeta{Kind: "response", Version: "v1.2.3", ID: 1}
+	stream1 := NewStream(ConfigDefault, nil, 64)
+	stream1.WriteVal(v1)
+	should.Equal(`{"kind":"response","version":"v1.2.3","id":1}`, string(stream1.Buffer()))
+
+	// A second value of the same type always gets the registered
+	// fragment for its frozen fields, regardless of what it actually
+	// holds there - RegisterFrozenField pins the constant up front
+	// instead of inferring it from whichever instance gets encoded
+	// first, so there's nothing for a later, differently-valued
+	// instance to corrupt.
+	v2 := responseMeta{Kind: "different", Version: "v9.9.9", ID: 2}
+	stream2 := NewStream(ConfigDefault, nil, 64)
+	stream2.WriteVal(v2)
+	should.Equal(`{"kind":"response","version":"v1.2.3","id":2}`, string(stream2.Buffer()))
+}
+
+type unregisteredFrozenMeta struct {
+	Kind string `json:"kind,frozen"`
+}
+
+func Test_WriteVal_frozenTag_withoutRegistrationEncodesLive(t *testing.T) {
+	should := require.New(t)
+
+	v1 := unregisteredFrozenMeta{Kind: "one"}
+	stream1 := NewStream(ConfigDefault, nil, 64)
+	stream1.WriteVal(v1)
+	should.Equal(`{"kind":"one"}`, string(stream1.Buffer()))
+
+	v2 := unregisteredFrozenMeta{Kind: "two"}
+	stream2 := NewStream(ConfigDefault, nil, 64)
+	stream2.WriteVal(v2)
+	should.Equal(`{"kind":"two"}`, string(stream2.Buffer()))
+}
+
+func Test_RegisterFrozenField_writesRawValueVerbatim(t *testing.T) {
+	should := require.New(t)
+	type widget struct {
+		Unit string `json:"unit,frozen"`
+	}
+	RegisterFrozenField(reflect.TypeOf(widget{}), "unit", []byte(`"cm"`))
+
+	stream := NewStream(ConfigDefault, nil, 64)
+	stream.WriteVal(widget{Unit: "anything"})
+	should.Equal(`{"unit":"cm"}`, string(stream.Buffer()))
+}
+
+func Test_WriteRawField(t *testing.T) {
+	should := require.New(t)
+	stream := NewStream(ConfigDefault, nil, 64)
+	stream.WriteObjectStart()
+	stream.WriteRawField("version", []byte(`"v1.2.3"`))
+	stream.WriteObjectEnd()
+	should.Equal(`{"version":"v1.2.3"}`, string(stream.Buffer()))
+}
+
+func BenchmarkFrozenFieldEncoder(b *testing.B) {
+	encoder := newFrozenFieldEncoder([]byte(`"version":"v1.2.3"`))
+	stream := NewStream(ConfigDefault, nil, 256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.buf = stream.buf[:0]
+		encoder.Encode(nil, stream)
+	}
+}
+
+func BenchmarkUnfrozenFieldEncoder(b *testing.B) {
+	inner := &fakeValEncoder{write: func(stream *Stream) {
+		stream.WriteString("v1.2.3")
+	}}
+	stream := NewStream(ConfigDefault, nil, 256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.buf = stream.buf[:0]
+		stream.WriteObjectField("version")
+		inner.Encode(nil, stream)
+	}
+}
@@ -0,0 +1,106 @@
+package jsoniter
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// reflectFieldEncoder is a generic ValEncoder for a struct field reached
+// via a fixed offset from the struct's base pointer. It falls back to
+// writeReflectVal for the actual value encoding, so it supports any
+// field type WriteVal does.
+type reflectFieldEncoder struct {
+	offset uintptr
+	typ    reflect.Type
+}
+
+func (encoder *reflectFieldEncoder) rv(ptr unsafe.Pointer) reflect.Value {
+	return reflect.NewAt(encoder.typ, unsafe.Pointer(uintptr(ptr)+encoder.offset)).Elem()
+}
+
+func (encoder *reflectFieldEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.rv(ptr).IsZero()
+}
+
+func (encoder *reflectFieldEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	stream.writeReflectVal(encoder.rv(ptr))
+}
+
+// structFieldEncoder writes a field's `"name":value` pair by pairing a
+// field name with the ValEncoder that writes its value.
+type structFieldEncoder struct {
+	fieldName  string
+	valEncoder ValEncoder
+}
+
+func (encoder *structFieldEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.valEncoder.IsEmpty(ptr)
+}
+
+func (encoder *structFieldEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	stream.WriteObjectField(encoder.fieldName)
+	encoder.valEncoder.Encode(ptr, stream)
+}
+
+// structEncoder is the compiled, cached encoder for one struct type: one
+// ValEncoder per JSON-visible field, in declaration order.
+type structEncoder struct {
+	typ    reflect.Type
+	fields []ValEncoder
+}
+
+var structEncoderCache sync.Map // map[reflect.Type]*structEncoder
+
+// encoderOfStruct returns the cached structEncoder for typ, building and
+// caching one on first use.
+func encoderOfStruct(typ reflect.Type) *structEncoder {
+	if cached, ok := structEncoderCache.Load(typ); ok {
+		return cached.(*structEncoder)
+	}
+	enc := buildStructEncoder(typ)
+	actual, _ := structEncoderCache.LoadOrStore(typ, enc)
+	return actual.(*structEncoder)
+}
+
+// buildStructEncoder walks typ's fields and compiles one ValEncoder per
+// JSON-visible field, honoring the `json:"name"` tag.
+func buildStructEncoder(typ reflect.Type) *structEncoder {
+	enc := &structEncoder{typ: typ}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := field.Name
+		options := ""
+		if idx := strings.IndexByte(tag, ','); idx != -1 {
+			if tag[:idx] != "" {
+				name = tag[:idx]
+			}
+			options = tag[idx+1:]
+		} else if tag != "" {
+			name = tag
+		}
+		if hasFrozenOption(options) {
+			if fragment, ok := frozenFieldRegistry.Load(frozenFieldKey{typ: typ, field: name}); ok {
+				// A registered fragment writes the full `"name":value`
+				// fragment itself, so it replaces structFieldEncoder
+				// rather than being wrapped by it.
+				enc.fields = append(enc.fields, newFrozenFieldEncoder(fragment.([]byte)))
+				continue
+			}
+			// No registration for this type/field yet - fall back to
+			// encoding it live rather than caching whatever the first
+			// encoded instance's value happens to be.
+		}
+		valEncoder := ValEncoder(&reflectFieldEncoder{offset: field.Offset, typ: field.Type})
+		enc.fields = append(enc.fields, &structFieldEncoder{fieldName: name, valEncoder: valEncoder})
+	}
+	return enc
+}
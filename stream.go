@@ -0,0 +1,210 @@
+package jsoniter
+
+import (
+	"io"
+	"strconv"
+)
+
+// Stream writes JSON values. Like Iterator, it reports failures via the
+// Error field instead of return values everywhere.
+type Stream struct {
+	cfg        *frozenConfig
+	out        io.Writer
+	buf        []byte
+	indention  int
+	level      int
+	Error      error
+	Attachment interface{}
+
+	// compressionCodec is set by NewCompressedStream when out is piped
+	// through a CompressionCodec, so FlushCompressed/CloseCompressed can
+	// reach it; see stream_compressed.go. Plain Streams leave it nil.
+	compressionCodec CompressionCodec
+
+	// rawBuf is the un-clipped buffer acquireStreamBuf handed back - buf
+	// is reslice-capped from it to the bufSize the caller actually asked
+	// for, so a Stream never grows its buffer past that size on its own
+	// (see Test_flush_buffer_should_stop_grow_buffer). Release pools
+	// rawBuf, not buf: pooling the clipped view would misreport its
+	// capacity to streamBufPool, which files buffers by capacity.
+	rawBuf []byte
+}
+
+// NewStream creates a Stream that writes to out (nil keeps everything in
+// the in-memory buffer, retrievable via Buffer), using a buffer of at
+// least bufSize bytes.
+func NewStream(cfg API, out io.Writer, bufSize int) *Stream {
+	fc := cfg.(*frozenConfig)
+	raw := acquireStreamBuf(fc, bufSize)
+	return &Stream{cfg: fc, out: out, buf: raw[:0:bufSize], rawBuf: raw, indention: fc.indentionStep}
+}
+
+// Reset reuses this Stream to write to a new writer, keeping its current
+// buffer.
+func (stream *Stream) Reset(out io.Writer) {
+	stream.out = out
+	stream.buf = stream.buf[:0]
+	stream.level = 0
+	stream.Error = nil
+}
+
+// Buffer returns the portion of the Stream's internal buffer written so
+// far that has not yet been flushed to its writer.
+func (stream *Stream) Buffer() []byte {
+	return stream.buf
+}
+
+// SetBuffer replaces the Stream's internal buffer outright.
+func (stream *Stream) SetBuffer(buf []byte) {
+	stream.buf = buf
+}
+
+// Write implements io.Writer by appending p to the Stream's buffer.
+func (stream *Stream) Write(p []byte) (int, error) {
+	stream.buf = append(stream.buf, p...)
+	return len(p), nil
+}
+
+func (stream *Stream) writeByte(c byte) {
+	stream.buf = append(stream.buf, c)
+}
+
+func (stream *Stream) writeTwoBytes(c1, c2 byte) {
+	stream.buf = append(stream.buf, c1, c2)
+}
+
+func (stream *Stream) writeThreeBytes(c1, c2, c3 byte) {
+	stream.buf = append(stream.buf, c1, c2, c3)
+}
+
+// WriteRaw appends s to the buffer verbatim, without quoting or escaping.
+func (stream *Stream) WriteRaw(s string) {
+	stream.buf = append(stream.buf, s...)
+}
+
+// WriteNil writes a JSON null.
+func (stream *Stream) WriteNil() {
+	stream.buf = append(stream.buf, 'n', 'u', 'l', 'l')
+}
+
+// WriteTrue writes a JSON true.
+func (stream *Stream) WriteTrue() {
+	stream.buf = append(stream.buf, 't', 'r', 'u', 'e')
+}
+
+// WriteFalse writes a JSON false.
+func (stream *Stream) WriteFalse() {
+	stream.buf = append(stream.buf, 'f', 'a', 'l', 's', 'e')
+}
+
+// WriteBool writes val as a JSON true/false.
+func (stream *Stream) WriteBool(val bool) {
+	if val {
+		stream.WriteTrue()
+	} else {
+		stream.WriteFalse()
+	}
+}
+
+// WriteInt writes val as a JSON number.
+func (stream *Stream) WriteInt(val int) {
+	stream.buf = strconv.AppendInt(stream.buf, int64(val), 10)
+}
+
+// WriteString writes val as a quoted JSON string. Only the double quote,
+// backslash, newline, tab and carriage return are escaped; other control
+// characters are written as-is.
+func (stream *Stream) WriteString(val string) {
+	stream.buf = append(stream.buf, '"')
+	for _, r := range val {
+		switch r {
+		case '"':
+			stream.buf = append(stream.buf, '\\', '"')
+		case '\\':
+			stream.buf = append(stream.buf, '\\', '\\')
+		case '\n':
+			stream.buf = append(stream.buf, '\\', 'n')
+		case '\t':
+			stream.buf = append(stream.buf, '\\', 't')
+		case '\r':
+			stream.buf = append(stream.buf, '\\', 'r')
+		default:
+			stream.buf = append(stream.buf, string(r)...)
+		}
+	}
+	stream.buf = append(stream.buf, '"')
+}
+
+// WriteObjectField writes field as a quoted key followed by a colon.
+func (stream *Stream) WriteObjectField(field string) {
+	stream.WriteString(field)
+	stream.buf = append(stream.buf, ':')
+}
+
+// WriteArrayStart writes the opening bracket of a JSON array, indenting
+// the first element if the Stream was configured with IndentionStep.
+func (stream *Stream) WriteArrayStart() {
+	stream.level++
+	stream.buf = append(stream.buf, '[')
+	stream.writeIndention()
+}
+
+// WriteArrayEnd writes the closing bracket of a JSON array.
+func (stream *Stream) WriteArrayEnd() {
+	stream.level--
+	stream.writeIndention()
+	stream.buf = append(stream.buf, ']')
+}
+
+// WriteObjectStart writes the opening brace of a JSON object.
+func (stream *Stream) WriteObjectStart() {
+	stream.level++
+	stream.buf = append(stream.buf, '{')
+	stream.writeIndention()
+}
+
+// WriteObjectEnd writes the closing brace of a JSON object.
+func (stream *Stream) WriteObjectEnd() {
+	stream.level--
+	stream.writeIndention()
+	stream.buf = append(stream.buf, '}')
+}
+
+// WriteMore writes the separator between two elements of an array or
+// object.
+func (stream *Stream) WriteMore() {
+	stream.buf = append(stream.buf, ',')
+	stream.writeIndention()
+}
+
+// writeIndention writes a newline plus enough spaces for the current
+// nesting level, when the Stream has IndentionStep configured.
+func (stream *Stream) writeIndention() {
+	if stream.indention == 0 {
+		return
+	}
+	stream.buf = append(stream.buf, '\n')
+	for i := 0; i < stream.level*stream.indention; i++ {
+		stream.buf = append(stream.buf, ' ')
+	}
+}
+
+// Flush writes the Stream's buffered bytes to its underlying writer and
+// empties the buffer. It is a no-op for a Stream with no writer.
+func (stream *Stream) Flush() error {
+	if stream.out == nil {
+		return nil
+	}
+	if stream.Error != nil {
+		return stream.Error
+	}
+	_, err := stream.out.Write(stream.buf)
+	if err != nil {
+		if stream.Error == nil {
+			stream.Error = err
+		}
+		return err
+	}
+	stream.buf = stream.buf[:0]
+	return nil
+}
@@ -0,0 +1,137 @@
+package jsoniter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+)
+
+// CompressionCodec lets NewCompressedStream / NewCompressedIterator plug
+// in any streaming compressor: gzip out of the box via GzipCodec, or a
+// third-party zstd/s2/snappy implementation that satisfies this
+// interface. Flush is expected to emit a restart point the decompressor
+// can resync on, so that calling it at JSON structural boundaries gives
+// block-based formats like zstd a natural, seekable record boundary
+// instead of flushing mid-token.
+type CompressionCodec interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+// GzipCodec adapts compress/gzip.Writer to CompressionCodec.
+type GzipCodec struct {
+	*gzip.Writer
+}
+
+// NewGzipCodec wraps w in a gzip.Writer usable as a CompressionCodec.
+func NewGzipCodec(w io.Writer) *GzipCodec {
+	return &GzipCodec{gzip.NewWriter(w)}
+}
+
+// NewCompressedStream returns a Stream whose output is piped through
+// codec. codec may be nil, in which case a GzipCodec writing to w is
+// used; a non-nil codec writes to its own destination and w is not used.
+// Flush() (and FlushCompressed(), which also flushes the codec)
+// are meant to be called at the same structural boundaries plain Stream
+// callers already use - end of a top-level value, end of each array
+// element - exactly what Test_flush_buffer_should_stop_grow_buffer
+// exercises for uncompressed Streams, so the uncompressed stream.buf
+// here never grows past bufSize either.
+func NewCompressedStream(cfg API, w io.Writer, codec CompressionCodec, bufSize int) *Stream {
+	if codec == nil {
+		codec = NewGzipCodec(w)
+	}
+	stream := NewStream(cfg, codec, bufSize)
+	stream.compressionCodec = codec
+	return stream
+}
+
+// FlushCompressed flushes the Stream's own buffer into its compression
+// codec and then flushes the codec itself, so the underlying writer
+// ends up with a complete, independently-decompressible chunk. It is a
+// no-op error-wise (falls back to plain Flush) on a Stream that was not
+// created via NewCompressedStream.
+func (stream *Stream) FlushCompressed() error {
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	if stream.compressionCodec != nil {
+		return stream.compressionCodec.Flush()
+	}
+	return nil
+}
+
+// CloseCompressed flushes and closes the underlying CompressionCodec. It
+// is a no-op if the Stream was not created via NewCompressedStream.
+func (stream *Stream) CloseCompressed() error {
+	if stream.compressionCodec == nil {
+		return nil
+	}
+	if err := stream.FlushCompressed(); err != nil {
+		return err
+	}
+	return stream.compressionCodec.Close()
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// newZstdReader is nil by default since zstd is not in the standard
+// library. A third-party integration plugs one in during init:
+//
+//	jsoniter.RegisterZstdReader(func(r io.Reader) (io.Reader, error) {
+//	    return zstd.NewReader(r)
+//	})
+var newZstdReader func(io.Reader) (io.Reader, error)
+
+// RegisterZstdReader plugs a zstd decompressor into
+// NewCompressedIterator so it can auto-detect zstd-framed input without
+// this package importing a zstd library directly.
+func RegisterZstdReader(newReader func(io.Reader) (io.Reader, error)) {
+	newZstdReader = newReader
+}
+
+// NewCompressedIterator sniffs the first bytes of r for the gzip or
+// zstd magic number and, if one matches, wraps r in the corresponding
+// decompressor before handing it to Parse. Unrecognized input is parsed
+// as plain JSON, so callers can point this at either compressed or
+// uncompressed input without branching themselves.
+func NewCompressedIterator(cfg API, r io.Reader, bufSize int) *Iterator {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	switch {
+	case err == nil && hasMagicPrefix(magic, gzipMagic):
+		gzr, gzErr := gzip.NewReader(br)
+		if gzErr != nil {
+			iter := Parse(cfg, br, bufSize)
+			iter.ReportError("NewCompressedIterator", gzErr.Error())
+			return iter
+		}
+		return Parse(cfg, gzr, bufSize)
+	case err == nil && hasMagicPrefix(magic, zstdMagic) && newZstdReader != nil:
+		zr, zErr := newZstdReader(br)
+		if zErr != nil {
+			iter := Parse(cfg, br, bufSize)
+			iter.ReportError("NewCompressedIterator", zErr.Error())
+			return iter
+		}
+		return Parse(cfg, zr, bufSize)
+	default:
+		return Parse(cfg, br, bufSize)
+	}
+}
+
+func hasMagicPrefix(b, prefix []byte) bool {
+	if len(b) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if b[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
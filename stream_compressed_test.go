@@ -0,0 +1,109 @@
+package jsoniter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// capTrackingCodec wraps a CompressionCodec and records the largest
+// buffer capacity ever passed to Write, mirroring the NopWriter pattern
+// in stream_test.go. Stream.Flush passes stream.buf straight through to
+// the codec's Write, so this is how "the uncompressed Stream.buf never
+// grows past bufSize" actually gets checked - asserting on
+// len(stream.buf)/cap(stream.buf) after CloseCompressed is too late,
+// since CloseCompressed always flushes the buffer back to length zero
+// first.
+type capTrackingCodec struct {
+	CompressionCodec
+	maxCap int
+}
+
+func (c *capTrackingCodec) Write(p []byte) (int, error) {
+	if cap(p) > c.maxCap {
+		c.maxCap = cap(p)
+	}
+	return c.CompressionCodec.Write(p)
+}
+
+func Test_CompressedStream_gzipRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+	should := require.New(t)
+
+	var buf bytes.Buffer
+	codec := &capTrackingCodec{CompressionCodec: NewGzipCodec(&buf)}
+	stream := NewCompressedStream(ConfigDefault, &buf, codec, 512)
+	stream.WriteArrayStart()
+	// FlushCompressed every element, like Test_flush_buffer_should_stop_grow_buffer
+	// does for a plain Stream - flushing only every Nth element lets
+	// stream.buf accumulate N elements' worth of bytes in between,
+	// which has nothing to do with bufSize and will always blow past it
+	// for a large enough N.
+	const n = 20000
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteInt(i % 10)
+		should.Nil(stream.FlushCompressed())
+	}
+	stream.WriteArrayEnd()
+	should.Nil(stream.CloseCompressed())
+	should.LessOrEqual(codec.maxCap, 512)
+
+	gzr, err := gzip.NewReader(&buf)
+	should.Nil(err)
+	decoded, err := io.ReadAll(gzr)
+	should.Nil(err)
+	should.True(bytes.HasPrefix(decoded, []byte("[")))
+	should.True(bytes.HasSuffix(decoded, []byte("]")))
+}
+
+// fakeCodec is a minimal CompressionCodec used to exercise the pluggable
+// interface without depending on a real third-party s2/snappy library.
+type fakeCodec struct {
+	w       io.Writer
+	flushes int
+}
+
+func (c *fakeCodec) Write(p []byte) (int, error) { return c.w.Write(p) }
+func (c *fakeCodec) Flush() error                { c.flushes++; return nil }
+func (c *fakeCodec) Close() error                { return nil }
+
+func Test_CompressedStream_pluggableCodec(t *testing.T) {
+	should := require.New(t)
+	var buf bytes.Buffer
+	codec := &fakeCodec{w: &buf}
+	stream := NewCompressedStream(ConfigDefault, &buf, codec, 64)
+	stream.WriteArrayStart()
+	stream.WriteInt(1)
+	stream.WriteMore()
+	stream.WriteInt(2)
+	stream.WriteArrayEnd()
+	should.Nil(stream.CloseCompressed())
+	should.Equal("[1,2]", buf.String())
+	should.GreaterOrEqual(codec.flushes, 1)
+}
+
+func Test_NewCompressedIterator_sniffsGzip(t *testing.T) {
+	should := require.New(t)
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	_, err := gzw.Write([]byte(`[1,2,3]`))
+	should.Nil(err)
+	should.Nil(gzw.Close())
+
+	iter := NewCompressedIterator(ConfigDefault, &buf, 512)
+	should.Nil(iter.Error)
+}
+
+func Test_NewCompressedIterator_plainJSON(t *testing.T) {
+	should := require.New(t)
+	iter := NewCompressedIterator(ConfigDefault, bytes.NewReader([]byte(`[1,2,3]`)), 512)
+	should.Nil(iter.Error)
+}
@@ -0,0 +1,12 @@
+package jsoniter
+
+// WriteRawField writes `"name":` followed by raw verbatim bytes, without
+// running either side through an encoder. RegisterFrozenField builds a
+// frozen field's fragment with it; callers can also use it to splice a
+// precomputed fragment into an object by hand. Encode never fills in a
+// frozen field's fragment: a frozen field with no registration is
+// encoded live.
+func (stream *Stream) WriteRawField(name string, raw []byte) {
+	stream.WriteObjectField(name)
+	stream.Write(raw)
+}
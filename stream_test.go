@@ -206,3 +206,37 @@ outer:
 	}
 	return *(*string)(unsafe.Pointer(&str))
 }
+
+// readNumberAsString is the naive baseline BenchmarkNoAlloc compares
+// against: allocate a fresh slice and grow it byte by byte.
+func (iter *Iterator) readNumberAsString() (ret string) {
+	var str []byte
+	for {
+		for i := iter.head; i < iter.tail; i++ {
+			c := iter.buf[i]
+			switch c {
+			case '+', '-', '.', 'e', 'E', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				str = append(str, c)
+			default:
+				iter.head = i
+				if iter.Error != nil && iter.Error != io.EOF {
+					return
+				}
+				if len(str) == 0 {
+					iter.ReportError("readNumberAsString", "invalid number")
+				}
+				return string(str)
+			}
+		}
+		if !iter.loadMore() {
+			break
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	if len(str) == 0 {
+		iter.ReportError("readNumberAsString", "invalid number")
+	}
+	return string(str)
+}